@@ -0,0 +1,108 @@
+// public domain
+
+package nnls
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+)
+
+// SCABatch solves the non-negative least squares problem for a shared
+// design matrix A against many response vectors B[j].
+//
+// Argument A represents a design or input matrix.  Each element of A must
+// have the same length.  Each B[j] represents a measurement or output
+// vector and must have the same length as A.  δ is a tolerance for the
+// result, applied independently to each solve.
+//
+// H = AᵀA is computed once and reused for every column of B, which
+// dominates runtime for tall A and large batches (H would otherwise be
+// recomputed, and cost O(mn²), on every call to SCA).  The per-column
+// solves are independent of one another and are run concurrently across
+// a worker pool sized from runtime.GOMAXPROCS.
+//
+// The result X returns one set of coefficients per B[j], in the same
+// order as B.  iters[j] is the number of iterations performed solving
+// B[j].  An error is returned if any B[j] is not the same length as A.
+func SCABatch(A [][]float64, B [][]float64, δ float64) (X [][]float64, iters []int, err error) {
+	m := len(A)
+	for _, Bj := range B {
+		if len(Bj) != m {
+			return nil, nil, errors.New("A, each B[j] must be same length")
+		}
+	}
+	H := buildH(A)
+
+	X = make([][]float64, len(B))
+	iters = make([]int, len(B))
+
+	jobs := make(chan int)
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(B) {
+		workers = len(B)
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				f := negAtb(A, B[j])
+				x, i, _ := NNQP(H, f, δ)
+				X[j] = x
+				iters[j] = i
+			}
+		}()
+	}
+	for j := range B {
+		jobs <- j
+	}
+	close(jobs)
+	wg.Wait()
+	return X, iters, nil
+}
+
+// buildH computes H = AᵀA.
+func buildH(A [][]float64) [][]float64 {
+	n := len(A[0])
+	H := make([][]float64, n)
+	for i := range H {
+		Hi := make([]float64, n)
+		for j := 0; j < i; j++ {
+			Hi[j] = H[j][i]
+		}
+		s := 0.
+		for k := range A {
+			e := A[k][i]
+			s += e * e
+		}
+		Hi[i] = s
+		for j := i + 1; j < n; j++ {
+			s := 0.
+			for k := range A {
+				s += A[k][i] * A[k][j]
+			}
+			Hi[j] = s
+		}
+		H[i] = Hi
+	}
+	return H
+}
+
+// negAtb computes f = −Aᵀb.
+func negAtb(A [][]float64, b []float64) []float64 {
+	n := len(A[0])
+	f := make([]float64, n)
+	for j := range f {
+		e := 0.
+		for i, bi := range b {
+			e -= bi * A[i][j]
+		}
+		f[j] = e
+	}
+	return f
+}
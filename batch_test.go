@@ -0,0 +1,40 @@
+// public domain
+
+package nnls_test
+
+import (
+	"testing"
+
+	"github.com/soniakeys/nnls"
+)
+
+func TestSCABatch(t *testing.T) {
+	// First column reuses blsB (expected [3,0]); second column negates
+	// blsB, which flips the sign of each coordinate's unconstrained
+	// optimum: x1 becomes negative and clamps to 0, x2 becomes positive
+	// (2) and stays there.
+	B := [][]float64{blsB, {-2, -4, 1, 3}}
+	X, iters, err := nnls.SCABatch(blsA, B, 1e-9)
+	if err != nil {
+		t.Fatalf("SCABatch: %v", err)
+	}
+	if len(X) != 2 || len(iters) != 2 {
+		t.Fatalf("SCABatch: got %d results, want 2", len(X))
+	}
+	want := [][]float64{{3, 0}, {0, 2}}
+	for j, w := range want {
+		if !approxEqualVec(X[j], w, 1e-6) {
+			t.Errorf("SCABatch X[%d] = %v, want %v", j, X[j], w)
+		}
+		if iters[j] <= 0 {
+			t.Errorf("SCABatch iters[%d] = %d, want > 0", j, iters[j])
+		}
+	}
+}
+
+func TestSCABatchErrors(t *testing.T) {
+	B := [][]float64{{1, 2}}
+	if _, _, err := nnls.SCABatch(blsA, B, 1e-6); err == nil {
+		t.Error("B[j] length mismatch: got nil error, want error")
+	}
+}
@@ -0,0 +1,97 @@
+// public domain
+
+package nnls
+
+import "errors"
+
+// BLS solves the box-constrained least squares problem.
+//
+// Argument A represents a design or input matrix.  Each element of A must
+// have the same length.  b represents a measurement or output vector.
+// lo and hi give elementwise lower and upper bounds on the result, and must
+// have the same length as elements of A.  A bound may be ±Inf to leave the
+// corresponding coordinate unconstrained on that side; passing lo all zero
+// and hi all +Inf reduces BLS to the problem solved by SCAKKT.  ε is a
+// tolerance for stopping iteration and must be > 0.
+//
+// Stopping criteria are the box-constrained generalization of the KKT
+// conditions used by SCAKKT: iteration stops once, for every k, one of
+// x[k]==lo[k] && μ[k]>=-ε, x[k]==hi[k] && μ[k]<=ε, or
+// lo[k]<x[k]<hi[k] && |μ[k]|<=ε holds, where μ=Hx+f is the gradient of
+// the objective.  BLS is a thin wrapper around Solve with opts.KKTTol
+// set to ε; callers that want the box-constrained generalization of
+// SCA's δ-gap criterion instead can call Solve directly with opts.Tol.
+//
+// The result x returns coefficients of the fitted linear function, it
+// will have the same length as elements of A.   Result i is the number of
+// iterations performed.  An error is returned if A and b are not the same
+// length, or if lo and hi are not the same length as elements of A, or if
+// some lo[k] > hi[k].
+func BLS(A [][]float64, b []float64, lo, hi []float64, ε float64) (x []float64, i int, err error) {
+	n := len(A[0])
+	if len(b) != len(A) {
+		return nil, 0, errors.New("A, b must be same length")
+	}
+	if len(lo) != n || len(hi) != n {
+		return nil, 0, errors.New("lo, hi must have same length as elements of A")
+	}
+	for k, l := range lo {
+		if l > hi[k] {
+			return nil, 0, errors.New("lo[k] must not exceed hi[k]")
+		}
+	}
+	res, err := Solve(A, b, Options{Lo: lo, Hi: hi, KKTTol: ε})
+	if err != nil {
+		return nil, 0, err
+	}
+	return res.X, res.Iter, nil
+}
+
+// clamp restricts v to the interval [lo, hi].
+func clamp(v, lo, hi float64) float64 {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+// buildHf builds the normal equations H = AᵀA and f = −Aᵀb for a
+// least-squares problem, along with a copy Hd of the diagonal of H.
+func buildHf(A [][]float64, b []float64) (H [][]float64, Hd, f []float64) {
+	n := len(A[0])
+	f = make([]float64, n)
+	for j := range f {
+		e := 0.
+		for i, bi := range b {
+			e -= bi * A[i][j]
+		}
+		f[j] = e
+	}
+	H = make([][]float64, n)
+	Hd = make([]float64, n)
+	for i := range H {
+		Hi := make([]float64, n)
+		for j := 0; j < i; j++ {
+			Hi[j] = H[j][i]
+		}
+		s := 0.
+		for k := range b {
+			e := A[k][i]
+			s += e * e
+		}
+		Hi[i] = s
+		Hd[i] = s
+		for j := i + 1; j < n; j++ {
+			s := 0.
+			for k := range b {
+				s += A[k][i] * A[k][j]
+			}
+			Hi[j] = s
+		}
+		H[i] = Hi
+	}
+	return
+}
@@ -0,0 +1,56 @@
+// public domain
+
+package nnls_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/soniakeys/nnls"
+)
+
+// A has two orthogonal columns, so AᵀA is diagonal and the box-constrained
+// least squares problem decouples into two independent 1-D problems whose
+// optima are easy to check by hand: unconstrained x1=3, x2=-2, clamped to
+// x2>=0.
+var blsA = [][]float64{
+	{1, 0},
+	{1, 0},
+	{0, 1},
+	{0, 1},
+}
+var blsB = []float64{2, 4, -1, -3}
+
+func TestBLS(t *testing.T) {
+	// hi[0]=2 clamps the otherwise-unconstrained x1=3 down to 2; x2 is
+	// already driven to 0 by the plain non-negativity bound.
+	x, i, err := nnls.BLS(blsA, blsB, []float64{0, 0}, []float64{2, math.Inf(1)}, 1e-9)
+	if err != nil {
+		t.Fatalf("BLS: %v", err)
+	}
+	if i <= 0 {
+		t.Errorf("BLS: iterations = %d, want > 0", i)
+	}
+	want := []float64{2, 0}
+	if !approxEqualVec(x, want, 1e-6) {
+		t.Errorf("BLS = %v, want %v", x, want)
+	}
+}
+
+func TestBLSErrors(t *testing.T) {
+	cases := []struct {
+		name      string
+		A         [][]float64
+		b, lo, hi []float64
+	}{
+		{"length mismatch", blsA, []float64{1}, []float64{0, 0}, []float64{1, 1}},
+		{"lo length mismatch", blsA, blsB, []float64{0}, []float64{1, 1}},
+		{"hi length mismatch", blsA, blsB, []float64{0, 0}, []float64{1}},
+		{"lo greater than hi", blsA, blsB, []float64{0, 2}, []float64{1, 1}},
+	}
+	for _, c := range cases {
+		if _, _, err := nnls.BLS(c.A, c.b, c.lo, c.hi, 1e-6); err == nil {
+			t.Errorf("%s: got nil error, want error", c.name)
+		}
+	}
+}
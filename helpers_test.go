@@ -0,0 +1,24 @@
+// public domain
+
+package nnls_test
+
+import "math"
+
+// approxEqual reports whether x and y agree to within tol, used throughout
+// the package tests to check solutions computed on small, hand-verifiable
+// systems without depending on exact floating-point iteration counts.
+func approxEqual(x, y, tol float64) bool {
+	return math.Abs(x-y) <= tol
+}
+
+func approxEqualVec(x, y []float64, tol float64) bool {
+	if len(x) != len(y) {
+		return false
+	}
+	for i := range x {
+		if !approxEqual(x[i], y[i], tol) {
+			return false
+		}
+	}
+	return true
+}
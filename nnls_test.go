@@ -8,7 +8,7 @@ import (
 	"github.com/soniakeys/nnls"
 )
 
-func ExampleNNLS() {
+func ExampleSCA() {
 	// Wikipedia example data
 	height := []float64{1.47, 1.50, 1.52, 1.55, 1.57, 1.60, 1.63,
 		1.65, 1.68, 1.70, 1.73, 1.75, 1.78, 1.80, 1.83}
@@ -18,7 +18,7 @@ func ExampleNNLS() {
 	for i, h := range height {
 		A[i] = []float64{h * h, h, 1} // Vandermonde
 	}
-	β, n, _ := nnls.NNLS(A, weight, -1)
+	β, n, _ := nnls.SCA(A, weight, -1)
 	fmt.Printf("coefficents:  %.2f\n", β)
 	fmt.Println("iterations:  ", n)
 	fmt.Println("measured  modeled    error")
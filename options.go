@@ -0,0 +1,301 @@
+// public domain
+
+package nnls
+
+import (
+	"context"
+	"errors"
+	"math"
+)
+
+// Options configures Solve.
+type Options struct {
+	// Lo and Hi give elementwise lower and upper bounds on the result, as
+	// in BLS.  If both are nil, the plain non-negative constraint x >= 0
+	// is used, as in SCA.
+	Lo, Hi []float64
+
+	// Limit is a maximum number of iterations.  If 0, the package value
+	// Limit is used.
+	Limit int
+
+	// Tol is the objective-gap tolerance, as passed to SCA.  Iteration
+	// stops when the objective is within Tol of optimal.  If Tol is 0,
+	// the objective-gap criterion is not used.
+	Tol float64
+
+	// KKTTol is the KKT tolerance, as passed to SCAKKT.  If KKTTol is 0,
+	// the KKT criterion is not used.  At least one of Tol and KKTTol must
+	// be non-zero.
+	KKTTol float64
+
+	// Trace, if non-nil, is called every TraceEvery iterations (every
+	// iteration if TraceEvery <= 0) with the current iteration count,
+	// primal x, objective value F(x), and duality gap F(x)-LB(x).
+	Trace func(iter int, x []float64, obj, gap float64)
+
+	// TraceEvery sets the tracing interval; see Trace.
+	TraceEvery int
+
+	// Context, if non-nil, is checked every iteration; when it is
+	// cancelled, Solve stops and returns a Result with Converged false
+	// and StopReason "context".
+	Context context.Context
+}
+
+// Result is the outcome of a call to Solve.
+type Result struct {
+	// X is the solution.
+	X []float64
+	// Iter is the number of iterations performed.
+	Iter int
+	// Obj is the objective value F(x) = ½⟨x,Hx⟩ + ⟨x,f⟩ at X.
+	Obj float64
+	// LB is the lower bound on the optimal objective computed at X, used
+	// for the objective-gap stopping criterion.
+	LB float64
+	// KKTResidual is the largest KKT violation at X.
+	KKTResidual float64
+	// Converged is true if Solve stopped because a stopping tolerance was
+	// met or because a sweep left x unchanged ("no-change"), and false if
+	// it stopped because of the iteration limit or a cancelled Context.
+	Converged bool
+	// StopReason names why Solve stopped: "tol", "kkt", "no-change",
+	// "limit", or "context".
+	StopReason string
+}
+
+// Solve solves the box-constrained least squares problem
+//
+//	minimize  ½‖Ax−b‖²  subject to Lo <= x <= Hi
+//
+// as configured by opts.  It unifies SCA, SCAKKT, SCALimit, and BLS behind
+// a single entry point that also reports iteration progress via
+// opts.Trace and supports cancellation via opts.Context.
+//
+// An error is returned if A and b are not the same length, if opts.Lo/Hi
+// are invalid, or if neither opts.Tol nor opts.KKTTol is set.
+func Solve(A [][]float64, b []float64, opts Options) (Result, error) {
+	if len(b) != len(A) {
+		return Result{}, errors.New("A, b must be same length")
+	}
+	if opts.Tol == 0 && opts.KKTTol == 0 {
+		return Result{}, errors.New("at least one of Tol, KKTTol must be non-zero")
+	}
+	n := len(A[0])
+	// defaultBox marks the plain non-negative constraint used by SCA: the
+	// coordinatewise lower bound objectiveAndLB otherwise relies on is
+	// -Inf on this unbounded-above box, so that case needs the same
+	// global ub·min(μ) bound NNQP uses instead.
+	defaultBox := opts.Lo == nil && opts.Hi == nil
+	lo, hi, err := boxOrDefault(opts.Lo, opts.Hi, n)
+	if err != nil {
+		return Result{}, err
+	}
+
+	H, Hd, f := buildHf(A, b)
+	x := make([]float64, n)
+	for k := range x {
+		x[k] = clamp(x[k], lo[k], hi[k])
+	}
+	// μ = Hx + f; x may start away from 0 when clamped into the box.
+	μ := append([]float64{}, f...)
+	for k, Hk := range H {
+		for j, Hkj := range Hk {
+			μ[k] += Hkj * x[j]
+		}
+	}
+
+	// ub = Σ max(0, −f[k]/Hd[k]), as in NNQP; only meaningful, and only
+	// computed, for the default box.
+	var ub float64
+	if defaultBox {
+		for k, fk := range f {
+			if u := -fk / Hd[k]; u > 0 {
+				ub += u
+			}
+		}
+	}
+
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = Limit
+	}
+	traceEvery := opts.TraceEvery
+	if traceEvery <= 0 {
+		traceEvery = 1
+	}
+
+	Hx := make([]float64, n)
+	i := 1
+	reason := "limit"
+	converged := false
+	var obj, lb float64
+loop:
+	for ; i < limit; i++ {
+		if opts.Context != nil {
+			select {
+			case <-opts.Context.Done():
+				reason = "context"
+				break loop
+			default:
+			}
+		}
+		ch := false
+		for k, xk := range x {
+			b := clamp(xk-μ[k]/Hd[k], lo[k], hi[k])
+			if b == xk {
+				continue
+			}
+			x[k] = b
+			ch = true
+			b -= xk
+			for j, h := range H[k] {
+				μ[j] += b * h
+			}
+		}
+
+		// objectiveAndLB is O(n²); skip it when nothing needs it this
+		// iteration, so a KKTTol-only configuration keeps the cheaper
+		// per-iteration cost SCAKKT/NNQPKKT advertise over SCA/NNQP.
+		traceDue := opts.Trace != nil && i%traceEvery == 0
+		if opts.Tol != 0 || traceDue {
+			obj, lb = objectiveAndLB(H, f, x, μ, lo, hi, Hx, ub, defaultBox)
+			if traceDue {
+				opts.Trace(i, x, obj, obj-lb)
+			}
+		}
+
+		if opts.Tol != 0 && obj-lb <= opts.Tol {
+			reason = "tol"
+			converged = true
+			break
+		}
+		if opts.KKTTol != 0 && kktResidual(x, μ, lo, hi) <= opts.KKTTol {
+			reason = "kkt"
+			converged = true
+			break
+		}
+		if !ch {
+			reason = "no-change"
+			converged = true
+			break
+		}
+	}
+	obj, lb = objectiveAndLB(H, f, x, μ, lo, hi, Hx, ub, defaultBox)
+	if opts.Trace != nil {
+		opts.Trace(i, x, obj, obj-lb)
+	}
+	return Result{
+		X:           x,
+		Iter:        i,
+		Obj:         obj,
+		LB:          lb,
+		KKTResidual: kktResidual(x, μ, lo, hi),
+		Converged:   converged,
+		StopReason:  reason,
+	}, nil
+}
+
+// boxOrDefault validates opts.Lo, opts.Hi, filling in the default
+// non-negative box [0, +Inf) when both are nil.
+func boxOrDefault(optLo, optHi []float64, n int) (lo, hi []float64, err error) {
+	if optLo == nil && optHi == nil {
+		lo = make([]float64, n)
+		hi = make([]float64, n)
+		for k := range hi {
+			hi[k] = math.Inf(1)
+		}
+		return lo, hi, nil
+	}
+	if len(optLo) != n || len(optHi) != n {
+		return nil, nil, errors.New("Lo, Hi must have same length as elements of A")
+	}
+	for k, l := range optLo {
+		if l > optHi[k] {
+			return nil, nil, errors.New("Lo[k] must not exceed Hi[k]")
+		}
+	}
+	return optLo, optHi, nil
+}
+
+// objectiveAndLB computes the objective F(x) = ½⟨x,Hx⟩ + ⟨x,f⟩ and a
+// lower bound LB(x) on the optimum, reusing Hx as scratch space.
+//
+// F is convex quadratic, so for any feasible y, F(y) >= F(x) + μᵀ(y-x)
+// where μ=Hx+f is the gradient at x.  Minimizing the right side over y in
+// the box [lo,hi] gives a valid lower bound; the minimum is taken
+// coordinatewise, at lo[k] when μ[k]>0 and at hi[k] when μ[k]<0.  That
+// bound goes to -Inf on the default box, where hi is +Inf, so when
+// useGlobalBound is set (the box is the default) LB is instead the
+// ub·min(μ) bound NNQP uses, with ub the sum of unconstrained
+// per-coordinate optima computed by the caller; see NNQP for the
+// derivation.
+func objectiveAndLB(H [][]float64, f, x, μ, lo, hi, Hx []float64, ub float64, useGlobalBound bool) (obj, lb float64) {
+	for i, Hi := range H {
+		Hxi := 0.
+		for j, Hij := range Hi {
+			Hxi += Hij * x[j]
+		}
+		Hx[i] = Hxi
+	}
+	xHx := 0.
+	xf := 0.
+	for i, xi := range x {
+		xHx += xi * Hx[i]
+		xf += xi * f[i]
+	}
+	obj = xHx/2 + xf
+
+	lb = -xHx / 2
+	if useGlobalBound {
+		mμ := μ[0]
+		for _, μk := range μ[1:] {
+			if μk < mμ {
+				mμ = μk
+			}
+		}
+		lb += ub * mμ
+		return obj, lb
+	}
+	for k, μk := range μ {
+		switch {
+		case μk > 0:
+			lb += lo[k] * μk
+		case μk < 0:
+			lb += hi[k] * μk
+		}
+	}
+	return obj, lb
+}
+
+// kktResidual reports the largest box-KKT violation at x: for each k,
+// μ[k] below 0 when x[k]==lo[k], μ[k] above 0 when x[k]==hi[k], and
+// |μ[k]| otherwise, should all be ~0 at optimality.  A pinned coordinate
+// (lo[k]==hi[k]) is always optimal, as under the criteria documented on
+// BLS, one of which always holds when lo[k]==hi[k].
+func kktResidual(x, μ, lo, hi []float64) float64 {
+	r := 0.
+	for k, xk := range x {
+		var v float64
+		switch {
+		case lo[k] == hi[k]:
+			// x[k] is pinned; both bounds are simultaneously active
+			// and any μ[k] is consistent with optimality.
+		case xk <= lo[k]:
+			if μ[k] < 0 {
+				v = -μ[k]
+			}
+		case xk >= hi[k]:
+			if μ[k] > 0 {
+				v = μ[k]
+			}
+		default:
+			v = math.Abs(μ[k])
+		}
+		if v > r {
+			r = v
+		}
+	}
+	return r
+}
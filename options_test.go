@@ -0,0 +1,126 @@
+// public domain
+
+package nnls_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/soniakeys/nnls"
+)
+
+func TestSolveBox(t *testing.T) {
+	res, err := nnls.Solve(blsA, blsB, nnls.Options{
+		Lo:     []float64{0, 0},
+		Hi:     []float64{2, math.Inf(1)},
+		KKTTol: 1e-9,
+	})
+	if err != nil {
+		t.Fatalf("Solve: %v", err)
+	}
+	want := []float64{2, 0}
+	if !approxEqualVec(res.X, want, 1e-6) {
+		t.Errorf("Solve.X = %v, want %v", res.X, want)
+	}
+	if !res.Converged {
+		t.Errorf("Solve: Converged = false, want true (StopReason %q)", res.StopReason)
+	}
+}
+
+func TestSolveTol(t *testing.T) {
+	// Lo, Hi nil default to the plain non-negative box, reducing this to
+	// the same problem solved by TestNNQP: x=[3,0].
+	res, err := nnls.Solve(blsA, blsB, nnls.Options{Tol: 1e-9})
+	if err != nil {
+		t.Fatalf("Solve: %v", err)
+	}
+	want := []float64{3, 0}
+	if !approxEqualVec(res.X, want, 1e-6) {
+		t.Errorf("Solve.X = %v, want %v", res.X, want)
+	}
+	if !res.Converged {
+		t.Errorf("Solve: Converged = false, want true (StopReason %q)", res.StopReason)
+	}
+}
+
+// TestSolveTolMultiIteration is a regression test for objectiveAndLB's
+// lower bound on the default (unbounded, non-negative) box: TestSolveTol's
+// blsA/blsB is a single-iteration, diagonal-H problem, which converges
+// before the bound's behavior on later iterations matters.  This uses the
+// same non-diagonal-H, multi-iteration Vandermonde problem as ExampleSCA
+// and checks that Solve's Tol criterion fires at the same iteration as
+// SCA's equivalent δ-gap criterion, instead of running to the iteration
+// limit via "no-change" as it did when the bound went to -Inf.
+func TestSolveTolMultiIteration(t *testing.T) {
+	height := []float64{1.47, 1.50, 1.52, 1.55, 1.57, 1.60, 1.63,
+		1.65, 1.68, 1.70, 1.73, 1.75, 1.78, 1.80, 1.83}
+	weight := []float64{52.21, 53.12, 54.48, 55.84, 57.20, 58.57, 59.93,
+		61.29, 63.11, 64.47, 66.28, 68.10, 69.92, 72.19, 74.46}
+	A := make([][]float64, len(height))
+	for i, h := range height {
+		A[i] = []float64{h * h, h, 1}
+	}
+
+	wantX, wantIter, err := nnls.SCA(A, weight, 1e-6)
+	if err != nil {
+		t.Fatalf("SCA: %v", err)
+	}
+
+	res, err := nnls.Solve(A, weight, nnls.Options{Tol: 1e-6})
+	if err != nil {
+		t.Fatalf("Solve: %v", err)
+	}
+	if res.StopReason != "tol" {
+		t.Errorf("Solve: StopReason = %q, want %q", res.StopReason, "tol")
+	}
+	if !approxEqualVec(res.X, wantX, 1e-6) {
+		t.Errorf("Solve.X = %v, want %v", res.X, wantX)
+	}
+	if res.Iter != wantIter {
+		t.Errorf("Solve: Iter = %d, want %d (matching SCA's equivalent δ-gap criterion)", res.Iter, wantIter)
+	}
+}
+
+// TestSolvePinnedCoordinate is a regression test: a pinned coordinate
+// (Lo[k]==Hi[k]) held away from its unconstrained optimum, on the side
+// where the gradient is negative, must not be reported as a KKT
+// violation — both bounds are simultaneously active there, so any
+// gradient sign is consistent with optimality.
+func TestSolvePinnedCoordinate(t *testing.T) {
+	A := [][]float64{{1}, {1}}
+	b := []float64{10, 10} // unconstrained optimum is x=10
+	res, err := nnls.Solve(A, b, nnls.Options{
+		Lo:     []float64{3},
+		Hi:     []float64{3},
+		KKTTol: 1e-6,
+	})
+	if err != nil {
+		t.Fatalf("Solve: %v", err)
+	}
+	if !approxEqualVec(res.X, []float64{3}, 1e-9) {
+		t.Errorf("Solve.X = %v, want [3]", res.X)
+	}
+	if res.KKTResidual != 0 {
+		t.Errorf("Solve.KKTResidual = %v, want 0 for a pinned coordinate", res.KKTResidual)
+	}
+	if !res.Converged {
+		t.Errorf("Solve: Converged = false, want true (StopReason %q)", res.StopReason)
+	}
+}
+
+func TestSolveErrors(t *testing.T) {
+	if _, err := nnls.Solve(blsA, []float64{1}, nnls.Options{Tol: 1e-6}); err == nil {
+		t.Error("A, b length mismatch: got nil error, want error")
+	}
+	if _, err := nnls.Solve(blsA, blsB, nnls.Options{}); err == nil {
+		t.Error("neither Tol nor KKTTol set: got nil error, want error")
+	}
+	if _, err := nnls.Solve(blsA, blsB, nnls.Options{Lo: []float64{0}, Tol: 1e-6}); err == nil {
+		t.Error("Lo length mismatch: got nil error, want error")
+	}
+	if _, err := nnls.Solve(blsA, blsB, nnls.Options{
+		Lo: []float64{0, 2}, Hi: []float64{0, 1}, Tol: 1e-6,
+	}); err == nil {
+		t.Error("Lo[k] > Hi[k]: got nil error, want error")
+	}
+}
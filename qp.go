@@ -0,0 +1,213 @@
+// public domain
+
+package nnls
+
+import "errors"
+
+// NNQP solves the non-negative quadratic program
+//
+//	minimize  ½xᵀHx + fᵀx  subject to x ≥ 0.
+//
+// H must be a symmetric positive semi-definite matrix (as, for example,
+// AᵀA for some design matrix A), and f must have the same length as H.
+// δ is a tolerance for the result.
+//
+// NNQP is the core solver behind SCA: SCA builds H = AᵀA and f = −Aᵀb
+// from a least-squares problem and calls NNQP directly.  Callers that
+// already have normal equations, or a Gram matrix assembled incrementally
+// or out-of-core, can call NNQP directly and skip recomputing H from A
+// and b.
+//
+// Iteration will stop when the objective is within δ of an optimal
+// solution.
+//
+// The result x returns the solution, it will have the same length as H.
+// Result i is the number of iterations performed.  An error is returned
+// if H is not square, symmetric, and PSD-looking (non-negative diagonal),
+// or if f is not the same length as H.
+func NNQP(H [][]float64, f []float64, δ float64) (x []float64, i int, err error) {
+	Hd, err := validateH(H, f)
+	if err != nil {
+		return nil, 0, err
+	}
+	n := len(f)
+
+	x = make([]float64, n)
+	μ := append([]float64{}, f...)
+
+	// ub = Σ max(0, −f[i]/Hd[i]), the sum of unconstrained per-coordinate
+	// optima, an upper bound on the result x.  See section 2, inequality
+	// (2), of the paper cited in the package doc.
+	ub := 0.
+	for i, fi := range f {
+		if u := -fi / Hd[i]; u > 0 {
+			ub += u
+		}
+	}
+	Hx := make([]float64, n)
+	for i = 1; i < Limit; i++ {
+		ch := false
+		for k, xk := range x {
+			b := xk - μ[k]/Hd[k]
+			if b < 0 {
+				b = 0
+			}
+			if b == xk {
+				continue
+			}
+			x[k] = b
+			ch = true
+			b -= xk
+			for j, h := range H[k] {
+				μ[j] += b * h
+			}
+		}
+		for i, Hi := range H {
+			Hxi := 0.
+			for j, Hij := range Hi {
+				Hxi += Hij * x[j]
+			}
+			Hx[i] = Hxi
+		}
+		xHx := 0.
+		for i, xi := range x {
+			xHx += xi * Hx[i]
+		}
+		xf := 0.
+		for i, xi := range x {
+			xf += xi * f[i]
+		}
+		mHxf := μ[0]
+		for i := 1; i < n; i++ {
+			if m := μ[i]; m < mHxf {
+				mHxf = m
+			}
+		}
+		if xHx+xf-ub*mHxf <= δ {
+			break
+		}
+		if !ch {
+			break
+		}
+	}
+	return
+}
+
+// NNQPKKT is NNQP with KKT-based stopping criteria, analogous to how
+// SCAKKT relates to SCA.  ε is a tolerance for stopping iteration.
+func NNQPKKT(H [][]float64, f []float64, ε float64) (x []float64, i int, err error) {
+	Hd, err := validateH(H, f)
+	if err != nil {
+		return nil, 0, err
+	}
+	n := len(f)
+
+	x = make([]float64, n)
+	μ := append([]float64{}, f...)
+
+	nε := -ε
+	i = 1
+i:
+	for ; i < Limit; i++ {
+		ch := false
+		for k, xk := range x {
+			b := xk - μ[k]/Hd[k]
+			if b < 0 {
+				b = 0
+			}
+			if b == xk {
+				continue
+			}
+			x[k] = b
+			ch = true
+			b -= xk
+			for j, h := range H[k] {
+				μ[j] += b * h
+			}
+		}
+		if !ch {
+			break
+		}
+		for k, m := range μ {
+			xk := x[k]
+			if xk < 0 {
+				continue i
+			}
+			if m < nε {
+				continue i
+			}
+			if xk > 0 && m > ε {
+				continue i
+			}
+		}
+		break
+	}
+	return x, i, nil
+}
+
+// NNQPLimit is NNQP with an absolute iteration limit, analogous to how
+// SCALimit relates to SCA.  If argument limit is < 0, the package value
+// Limit is used.
+func NNQPLimit(H [][]float64, f []float64, limit int) (x []float64, i int, err error) {
+	Hd, err := validateH(H, f)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	x = make([]float64, len(f))
+	μ := append([]float64{}, f...)
+
+	if limit < 0 {
+		limit = Limit
+	}
+	i = 1
+	for ; i < limit; i++ {
+		ch := false
+		for k, xk := range x {
+			b := xk - μ[k]/Hd[k]
+			if b < 0 {
+				b = 0
+			}
+			if b == xk {
+				continue
+			}
+			x[k] = b
+			ch = true
+			b -= xk
+			for j, h := range H[k] {
+				μ[j] += b * h
+			}
+		}
+		if !ch {
+			break
+		}
+	}
+	return x, i, nil
+}
+
+// validateH checks that H is square with the same length as f and looks
+// like a symmetric PSD matrix, and returns its diagonal.
+func validateH(H [][]float64, f []float64) ([]float64, error) {
+	n := len(f)
+	if len(H) != n {
+		return nil, errors.New("H, f must be same length")
+	}
+	for _, Hi := range H {
+		if len(Hi) != n {
+			return nil, errors.New("H must be square")
+		}
+	}
+	Hd := make([]float64, n)
+	for i, Hi := range H {
+		if Hi[i] < 0 {
+			return nil, errors.New("H must be positive semi-definite")
+		}
+		Hd[i] = Hi[i]
+		for j, Hij := range Hi {
+			if Hij != H[j][i] {
+				return nil, errors.New("H must be symmetric")
+			}
+		}
+	}
+	return Hd, nil
+}
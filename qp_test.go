@@ -0,0 +1,78 @@
+// public domain
+
+package nnls_test
+
+import (
+	"testing"
+
+	"github.com/soniakeys/nnls"
+)
+
+// H, f are the normal equations for the orthogonal-column design in
+// bls_test.go: H=AᵀA=diag(2,2), f=-Aᵀb=[-6,4]. Unconstrained optima are
+// x1=3, x2=-2, so the non-negative solution clamps x2 to 0.
+var qpH = [][]float64{
+	{2, 0},
+	{0, 2},
+}
+var qpF = []float64{-6, 4}
+
+func TestNNQP(t *testing.T) {
+	x, i, err := nnls.NNQP(qpH, qpF, 1e-9)
+	if err != nil {
+		t.Fatalf("NNQP: %v", err)
+	}
+	if i <= 0 {
+		t.Errorf("NNQP: iterations = %d, want > 0", i)
+	}
+	want := []float64{3, 0}
+	if !approxEqualVec(x, want, 1e-6) {
+		t.Errorf("NNQP = %v, want %v", x, want)
+	}
+}
+
+func TestNNQPKKT(t *testing.T) {
+	x, i, err := nnls.NNQPKKT(qpH, qpF, 1e-9)
+	if err != nil {
+		t.Fatalf("NNQPKKT: %v", err)
+	}
+	if i <= 0 {
+		t.Errorf("NNQPKKT: iterations = %d, want > 0", i)
+	}
+	want := []float64{3, 0}
+	if !approxEqualVec(x, want, 1e-6) {
+		t.Errorf("NNQPKKT = %v, want %v", x, want)
+	}
+}
+
+func TestNNQPLimit(t *testing.T) {
+	x, i, err := nnls.NNQPLimit(qpH, qpF, -1)
+	if err != nil {
+		t.Fatalf("NNQPLimit: %v", err)
+	}
+	if i <= 0 {
+		t.Errorf("NNQPLimit: iterations = %d, want > 0", i)
+	}
+	want := []float64{3, 0}
+	if !approxEqualVec(x, want, 1e-6) {
+		t.Errorf("NNQPLimit = %v, want %v", x, want)
+	}
+}
+
+func TestNNQPErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		H    [][]float64
+		f    []float64
+	}{
+		{"H,f length mismatch", qpH, []float64{-6, 4, 0}},
+		{"H not square", [][]float64{{2, 0, 0}, {0, 2}}, qpF},
+		{"H not symmetric", [][]float64{{2, 1}, {0, 2}}, qpF},
+		{"H not PSD-looking", [][]float64{{-1, 0}, {0, 2}}, qpF},
+	}
+	for _, c := range cases {
+		if _, _, err := nnls.NNQP(c.H, c.f, 1e-6); err == nil {
+			t.Errorf("%s: got nil error, want error", c.name)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// public domain
+
+package nnls
+
+import "errors"
+
+// SCAReg solves the Tikhonov/LASSO-regularized non-negative least squares
+// problem
+//
+//	minimize  ½‖Ax−b‖² + (λ2/2)‖x‖² + λ1·⟨1,x⟩  subject to x ≥ 0.
+//
+// Argument A represents a design or input matrix.  Each element of A must
+// have the same length.  b represents a measurement or output vector.
+// λ2 is an L2 (ridge) penalty and λ1 is an L1 (LASSO) penalty; either or
+// both may be 0 to recover plain NNLS.  δ is a tolerance for the result.
+//
+// The regularizers are folded directly into the normal equations: λ2 is
+// added to the diagonal of H = AᵀA, and λ1 is added to f = −Aᵀb.  The
+// problem remains a non-negative QP of the same structure solved by SCA,
+// so SCAReg builds H and f and calls NNQP directly.
+//
+// The result x returns coefficients of the fitted linear function, it
+// will have the same length as elements of A.   Result i is the number of
+// iterations performed.  An error is returned if A and b are not the same
+// length, or if λ2 < 0.
+func SCAReg(A [][]float64, b []float64, λ2, λ1, δ float64) (x []float64, i int, err error) {
+	if len(b) != len(A) {
+		return nil, 0, errors.New("A, b must be same length")
+	}
+	if λ2 < 0 {
+		return nil, 0, errors.New("λ2 must not be negative")
+	}
+	H, f := regHf(A, b, constDiag(λ2), λ1)
+	return NNQP(H, f, δ)
+}
+
+// SCARegWeighted is SCAReg generalized to a per-feature ridge penalty.
+// Argument λ2 gives a non-negative regularization weight for each column
+// of A (coefficient of x), allowing different features to be shrunk by
+// different amounts; len(λ2) must equal the number of columns of A.
+// λ1 and δ are as in SCAReg.
+func SCARegWeighted(A [][]float64, b []float64, λ2 []float64, λ1, δ float64) (x []float64, i int, err error) {
+	if len(b) != len(A) {
+		return nil, 0, errors.New("A, b must be same length")
+	}
+	if len(λ2) != len(A[0]) {
+		return nil, 0, errors.New("λ2 must have same length as elements of A")
+	}
+	for _, l := range λ2 {
+		if l < 0 {
+			return nil, 0, errors.New("λ2 must not be negative")
+		}
+	}
+	H, f := regHf(A, b, weightedDiag(λ2), λ1)
+	return NNQP(H, f, δ)
+}
+
+// SCARegKKT is SCAReg with KKT-based stopping criteria, analogous to how
+// SCAKKT relates to SCA.  ε is a tolerance for stopping iteration.
+func SCARegKKT(A [][]float64, b []float64, λ2, λ1, ε float64) ([]float64, int, error) {
+	if len(b) != len(A) {
+		return nil, 0, errors.New("A, b must be same length")
+	}
+	if λ2 < 0 {
+		return nil, 0, errors.New("λ2 must not be negative")
+	}
+	H, f := regHf(A, b, constDiag(λ2), λ1)
+	return NNQPKKT(H, f, ε)
+}
+
+// SCARegLimit is SCAReg with an absolute iteration limit, analogous to how
+// SCALimit relates to SCA.  If argument limit is < 0, the package value
+// Limit is used.
+func SCARegLimit(A [][]float64, b []float64, λ2, λ1 float64, limit int) ([]float64, int, error) {
+	if len(b) != len(A) {
+		return nil, 0, errors.New("A, b must be same length")
+	}
+	if λ2 < 0 {
+		return nil, 0, errors.New("λ2 must not be negative")
+	}
+	H, f := regHf(A, b, constDiag(λ2), λ1)
+	return NNQPLimit(H, f, limit)
+}
+
+// constDiag returns a diagonal-regularization function constant across
+// every column, for the scalar-λ2 SCAReg variants.
+func constDiag(λ2 float64) func(k int) float64 {
+	return func(int) float64 { return λ2 }
+}
+
+// weightedDiag returns a diagonal-regularization function that looks up a
+// per-column weight, for SCARegWeighted.
+func weightedDiag(λ2 []float64) func(k int) float64 {
+	return func(k int) float64 { return λ2[k] }
+}
+
+// regHf builds the normal equations for a regularized least-squares
+// problem: H = AᵀA with diag(k) added to H[k][k], and f = −Aᵀb + λ1.
+func regHf(A [][]float64, b []float64, diag func(k int) float64, λ1 float64) (H [][]float64, f []float64) {
+	H, _, f = buildHf(A, b)
+	for k := range f {
+		f[k] += λ1
+		H[k][k] += diag(k)
+	}
+	return H, f
+}
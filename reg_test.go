@@ -0,0 +1,90 @@
+// public domain
+
+package nnls_test
+
+import (
+	"testing"
+
+	"github.com/soniakeys/nnls"
+)
+
+// regA, regB are the same orthogonal-column design as blsA/blsB, so the
+// regularized normal equations still decouple: unconstrained x1 =
+// (Aᵀb)[0]/(H[0][0]+λ2) after subtracting λ1, x2 similarly, clamped at 0.
+var regA = blsA
+var regB = blsB
+
+func TestSCAReg(t *testing.T) {
+	// H[0][0]=H[1][1]=2, (Aᵀb)[0]=6, (Aᵀb)[1]=-4. With λ2=2, λ1=0:
+	// x1=6/(2+2)=1.5, x2 stays clamped at 0.
+	x, i, err := nnls.SCAReg(regA, regB, 2, 0, 1e-9)
+	if err != nil {
+		t.Fatalf("SCAReg: %v", err)
+	}
+	if i <= 0 {
+		t.Errorf("SCAReg: iterations = %d, want > 0", i)
+	}
+	want := []float64{1.5, 0}
+	if !approxEqualVec(x, want, 1e-6) {
+		t.Errorf("SCAReg = %v, want %v", x, want)
+	}
+}
+
+func TestSCARegWeighted(t *testing.T) {
+	// Per-column λ2 = [2, 0] reduces to the SCAReg(λ2=2) case for x1 and
+	// the plain SCA case for x2.
+	x, i, err := nnls.SCARegWeighted(regA, regB, []float64{2, 0}, 0, 1e-9)
+	if err != nil {
+		t.Fatalf("SCARegWeighted: %v", err)
+	}
+	if i <= 0 {
+		t.Errorf("SCARegWeighted: iterations = %d, want > 0", i)
+	}
+	want := []float64{1.5, 0}
+	if !approxEqualVec(x, want, 1e-6) {
+		t.Errorf("SCARegWeighted = %v, want %v", x, want)
+	}
+}
+
+func TestSCARegKKT(t *testing.T) {
+	x, i, err := nnls.SCARegKKT(regA, regB, 2, 0, 1e-9)
+	if err != nil {
+		t.Fatalf("SCARegKKT: %v", err)
+	}
+	if i <= 0 {
+		t.Errorf("SCARegKKT: iterations = %d, want > 0", i)
+	}
+	want := []float64{1.5, 0}
+	if !approxEqualVec(x, want, 1e-6) {
+		t.Errorf("SCARegKKT = %v, want %v", x, want)
+	}
+}
+
+func TestSCARegLimit(t *testing.T) {
+	x, i, err := nnls.SCARegLimit(regA, regB, 2, 0, -1)
+	if err != nil {
+		t.Fatalf("SCARegLimit: %v", err)
+	}
+	if i <= 0 {
+		t.Errorf("SCARegLimit: iterations = %d, want > 0", i)
+	}
+	want := []float64{1.5, 0}
+	if !approxEqualVec(x, want, 1e-6) {
+		t.Errorf("SCARegLimit = %v, want %v", x, want)
+	}
+}
+
+func TestSCARegErrors(t *testing.T) {
+	if _, _, err := nnls.SCAReg(regA, []float64{1}, 1, 0, 1e-6); err == nil {
+		t.Error("SCAReg length mismatch: got nil error, want error")
+	}
+	if _, _, err := nnls.SCAReg(regA, regB, -1, 0, 1e-6); err == nil {
+		t.Error("SCAReg negative λ2: got nil error, want error")
+	}
+	if _, _, err := nnls.SCARegWeighted(regA, regB, []float64{1}, 0, 1e-6); err == nil {
+		t.Error("SCARegWeighted λ2 length mismatch: got nil error, want error")
+	}
+	if _, _, err := nnls.SCARegWeighted(regA, regB, []float64{1, -1}, 0, 1e-6); err == nil {
+		t.Error("SCARegWeighted negative λ2: got nil error, want error")
+	}
+}
@@ -0,0 +1,158 @@
+// public domain
+
+package nnls
+
+import "errors"
+
+// ShrinkPeriod controls the active-set shrinking used by SCAWarm: every
+// ShrinkPeriod iterations, and whenever a restricted sweep makes no
+// progress, SCAWarm performs a full sweep over every coordinate instead
+// of just the current working set.
+var ShrinkPeriod int = 10
+
+// ShrinkMargin is the margin used by SCAWarm to mark a coordinate
+// inactive: after a full sweep, x[k]==0 and μ[k]>ShrinkMargin together
+// mark coordinate k as unlikely to become non-zero again.  It is a
+// separate knob from the caller's solve tolerance δ, which may be far
+// tighter than is useful as a shrinking threshold.
+var ShrinkMargin float64 = 1e-6
+
+// SCAWarm solves the non-negative least squares problem, starting from a
+// caller-supplied initial guess x0 instead of the zero vector used by
+// SCA.
+//
+// Argument A represents a design or input matrix.  Each element of A must
+// have the same length.  b represents a measurement or output vector.
+// x0 is the initial guess; it must be feasible (non-negative) and have
+// the same length as elements of A.  δ is a tolerance for the result, as
+// in SCA.
+//
+// SCAWarm also applies an active-set shrinking heuristic: after a full
+// sweep over all coordinates, coordinates with x[k]==0 and μ[k]>ShrinkMargin
+// are unlikely to become non-zero again and are marked inactive, so that
+// following sweeps only visit the remaining, much smaller, working set.
+// A full sweep is still performed periodically (see ShrinkPeriod) and
+// whenever a restricted sweep stalls, so the δKKT stopping criterion,
+// which relies on a full sweep to be globally valid, is unaffected.
+//
+// The result x returns coefficients of the fitted linear function, it
+// will have the same length as elements of A.   Result i is the number of
+// iterations performed.  An error is returned if A and b are not the same
+// length, if x0 is not the same length as elements of A, or if x0 is not
+// feasible.
+func SCAWarm(A [][]float64, b []float64, x0 []float64, δ float64) (x []float64, i int, err error) {
+	m := len(A)
+	n := len(A[0])
+	if len(b) != m {
+		return nil, 0, errors.New("A, b must be same length")
+	}
+	if len(x0) != n {
+		return nil, 0, errors.New("x0 must have same length as elements of A")
+	}
+	for _, x0k := range x0 {
+		if x0k < 0 {
+			return nil, 0, errors.New("x0 must be feasible: non-negative")
+		}
+	}
+
+	H, Hd, f := buildHf(A, b)
+	x = append([]float64{}, x0...)
+
+	// μ = Hx0 + f, computed once; subsequent updates to μ are the usual
+	// incremental updates used by SCA.
+	μ := make([]float64, n)
+	for k, Hk := range H {
+		s := f[k]
+		for j, Hkj := range Hk {
+			s += Hkj * x[j]
+		}
+		μ[k] = s
+	}
+
+	ub := 0.
+	for k, fk := range f {
+		if u := -fk / Hd[k]; u > 0 {
+			ub += u
+		}
+	}
+
+	// active marks coordinates believed stuck at 0 and skipped on
+	// restricted sweeps; it is only trustworthy right after a full
+	// sweep, which is why stopping criteria are only checked then.
+	active := make([]bool, n)
+
+	Hx := make([]float64, n)
+	forceFull := false
+	for i = 1; i < Limit; i++ {
+		full := i == 1 || i%ShrinkPeriod == 0 || forceFull
+		forceFull = false
+		ch := false
+		for k, xk := range x {
+			if !full && active[k] {
+				continue
+			}
+			b := xk - μ[k]/Hd[k]
+			if b < 0 {
+				b = 0
+			}
+			if b == xk {
+				continue
+			}
+			x[k] = b
+			ch = true
+			b -= xk
+			for j, h := range H[k] {
+				μ[j] += b * h
+			}
+		}
+		if !full {
+			if !ch {
+				// no progress on the working set alone; the sweep we
+				// just ran skipped the active coordinates, so clear
+				// active and treat the next iteration as full instead
+				// of waiting for the next ShrinkPeriod boundary to
+				// re-check stopping and refresh active.
+				for k := range active {
+					active[k] = false
+				}
+				forceFull = true
+			}
+			continue
+		}
+
+		// compute Hx, for stopping criterion
+		for i, Hi := range H {
+			Hxi := 0.
+			for j, Hij := range Hi {
+				Hxi += Hij * x[j]
+			}
+			Hx[i] = Hxi
+		}
+		xHx := 0.
+		for i, xi := range x {
+			xHx += xi * Hx[i]
+		}
+		xf := 0.
+		for i, xi := range x {
+			xf += xi * f[i]
+		}
+		mHxf := μ[0]
+		for i := 1; i < n; i++ {
+			if m := μ[i]; m < mHxf {
+				mHxf = m
+			}
+		}
+		if xHx+xf-ub*mHxf <= δ {
+			break
+		}
+		if !ch {
+			break
+		}
+
+		// refresh the active set from the just-completed full sweep.
+		for k, xk := range x {
+			active[k] = xk == 0 && μ[k] > ShrinkMargin
+		}
+	}
+	return
+}
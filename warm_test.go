@@ -0,0 +1,78 @@
+// public domain
+
+package nnls_test
+
+import (
+	"testing"
+
+	"github.com/soniakeys/nnls"
+)
+
+func TestSCAWarmFromZero(t *testing.T) {
+	x0 := []float64{0, 0}
+	x, i, err := nnls.SCAWarm(blsA, blsB, x0, 1e-9)
+	if err != nil {
+		t.Fatalf("SCAWarm: %v", err)
+	}
+	if i <= 0 {
+		t.Errorf("SCAWarm: iterations = %d, want > 0", i)
+	}
+	want := []float64{3, 0}
+	if !approxEqualVec(x, want, 1e-6) {
+		t.Errorf("SCAWarm = %v, want %v", x, want)
+	}
+}
+
+func TestSCAWarmFromSolution(t *testing.T) {
+	// Starting already at the optimum should take very few iterations.
+	x0 := []float64{3, 0}
+	x, i, err := nnls.SCAWarm(blsA, blsB, x0, 1e-9)
+	if err != nil {
+		t.Fatalf("SCAWarm: %v", err)
+	}
+	want := []float64{3, 0}
+	if !approxEqualVec(x, want, 1e-6) {
+		t.Errorf("SCAWarm = %v, want %v", x, want)
+	}
+	if i > 3 {
+		t.Errorf("SCAWarm from the solution took %d iterations, want very few", i)
+	}
+}
+
+// TestSCAWarmShrinking checks that SCAWarm still reaches the correct
+// answer with a tight ShrinkPeriod and ShrinkMargin.  On blsA/blsB the
+// first full sweep already meets δ, so no restricted sweep and no
+// stall-triggered full-sweep fallback is run here.
+func TestSCAWarmShrinking(t *testing.T) {
+	origPeriod, origMargin := nnls.ShrinkPeriod, nnls.ShrinkMargin
+	nnls.ShrinkPeriod = 2
+	nnls.ShrinkMargin = 1e-9
+	t.Cleanup(func() {
+		nnls.ShrinkPeriod = origPeriod
+		nnls.ShrinkMargin = origMargin
+	})
+
+	x, i, err := nnls.SCAWarm(blsA, blsB, []float64{0, 0}, 1e-9)
+	if err != nil {
+		t.Fatalf("SCAWarm: %v", err)
+	}
+	if i <= 0 {
+		t.Errorf("SCAWarm: iterations = %d, want > 0", i)
+	}
+	want := []float64{3, 0}
+	if !approxEqualVec(x, want, 1e-6) {
+		t.Errorf("SCAWarm = %v, want %v", x, want)
+	}
+}
+
+func TestSCAWarmErrors(t *testing.T) {
+	if _, _, err := nnls.SCAWarm(blsA, []float64{1}, []float64{0, 0}, 1e-6); err == nil {
+		t.Error("A, b length mismatch: got nil error, want error")
+	}
+	if _, _, err := nnls.SCAWarm(blsA, blsB, []float64{0}, 1e-6); err == nil {
+		t.Error("x0 length mismatch: got nil error, want error")
+	}
+	if _, _, err := nnls.SCAWarm(blsA, blsB, []float64{-1, 0}, 1e-6); err == nil {
+		t.Error("x0 infeasible: got nil error, want error")
+	}
+}